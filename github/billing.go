@@ -0,0 +1,611 @@
+// Copyright 2021 The go-github AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package github
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BillingService provides access to the billing related functions
+// in the GitHub API.
+//
+// GitHub API docs: https://docs.github.com/rest/billing
+type BillingService service
+
+// ActionBilling represents a GitHub Actions billing.
+type ActionBilling struct {
+	TotalMinutesUsed     float64              `json:"total_minutes_used"`
+	TotalPaidMinutesUsed float64              `json:"total_paid_minutes_used"`
+	IncludedMinutes      float64              `json:"included_minutes"`
+	MinutesUsedBreakdown MinutesUsedBreakdown `json:"minutes_used_breakdown"`
+}
+
+// MinutesUsedBreakdown counts the actions minutes used by machine type.
+type MinutesUsedBreakdown map[string]int
+
+// PackageBilling represents a GitHub Packages billing.
+type PackageBilling struct {
+	TotalGigabytesBandwidthUsed     int `json:"total_gigabytes_bandwidth_used"`
+	TotalPaidGigabytesBandwidthUsed int `json:"total_paid_gigabytes_bandwidth_used"`
+	IncludedGigabytesBandwidth      int `json:"included_gigabytes_bandwidth"`
+}
+
+// StorageBilling represents a GitHub Storage billing.
+type StorageBilling struct {
+	DaysLeftInBillingCycle       int     `json:"days_left_in_billing_cycle"`
+	EstimatedPaidStorageForMonth float64 `json:"estimated_paid_storage_for_month"`
+	EstimatedStorageForMonth     float64 `json:"estimated_storage_for_month"`
+}
+
+// PremiumRequestsBilling represents the GitHub Copilot premium requests billing,
+// i.e. metered Copilot chat/completion usage.
+type PremiumRequestsBilling struct {
+	TotalRequests     int            `json:"total_requests"`
+	TotalPaidRequests int            `json:"total_paid_requests"`
+	IncludedRequests  int            `json:"included_requests"`
+	RequestsBreakdown map[string]int `json:"requests_breakdown"`
+}
+
+// AdvancedSecurityCommittersBreakdown represents the monthly GitHub Advanced Security
+// active committers for a repository.
+type AdvancedSecurityCommittersBreakdown struct {
+	UserLogin      *string `json:"user_login,omitempty"`
+	LastPushedDate *string `json:"last_pushed_date,omitempty"`
+}
+
+// RepositoryActiveCommitters represents the GitHub Advanced Security active committers
+// for a repository.
+type RepositoryActiveCommitters struct {
+	Name                                *string                                `json:"name,omitempty"`
+	AdvancedSecurityCommitters          *int                                   `json:"advanced_security_committers,omitempty"`
+	AdvancedSecurityCommittersBreakdown []*AdvancedSecurityCommittersBreakdown `json:"advanced_security_committers_breakdown,omitempty"`
+}
+
+// ActiveCommitters represents the GitHub Advanced Security active committers
+// for an organization.
+type ActiveCommitters struct {
+	TotalAdvancedSecurityCommitters     int                           `json:"total_advanced_security_committers"`
+	TotalCount                          int                           `json:"total_count"`
+	MaximumAdvancedSecurityCommitters   int                           `json:"maximum_advanced_security_committers"`
+	PurchasedAdvancedSecurityCommitters int                           `json:"purchased_advanced_security_committers"`
+	Repositories                        []*RepositoryActiveCommitters `json:"repositories"`
+}
+
+// UsageReportOptions specifies optional parameters to the
+// BillingService.GetUsageReportOrg and BillingService.GetUsageReportUser methods.
+type UsageReportOptions struct {
+	Year  *int `url:"year,omitempty"`
+	Month *int `url:"month,omitempty"`
+	Day   *int `url:"day,omitempty"`
+	Hour  *int `url:"hour,omitempty"`
+}
+
+// UsageReport represents the usage report for an organization/user.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-billing-usage-report-for-an-organization
+type UsageReport struct {
+	UsageItems []*UsageItem `json:"usageItems,omitempty"`
+}
+
+// UsageItem represents a single usage line item in a UsageReport.
+type UsageItem struct {
+	Date             *string  `json:"date,omitempty"`
+	Product          *string  `json:"product,omitempty"`
+	SKU              *string  `json:"sku,omitempty"`
+	Quantity         *float64 `json:"quantity,omitempty"`
+	UnitType         *string  `json:"unitType,omitempty"`
+	PricePerUnit     *float64 `json:"pricePerUnit,omitempty"`
+	GrossAmount      *float64 `json:"grossAmount,omitempty"`
+	DiscountAmount   *float64 `json:"discountAmount,omitempty"`
+	NetAmount        *float64 `json:"netAmount,omitempty"`
+	OrganizationName *string  `json:"organizationName,omitempty"`
+	RepositoryName   *string  `json:"repositoryName,omitempty"`
+}
+
+// GetActionsBillingOrg returns the summary of the free and paid GitHub Actions
+// minutes used for an org.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-github-actions-billing-for-an-organization
+func (s *BillingService) GetActionsBillingOrg(ctx context.Context, org string) (*ActionBilling, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/settings/billing/actions", org)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actionsBilling := new(ActionBilling)
+	resp, err := s.client.Do(ctx, req, actionsBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return actionsBilling, resp, nil
+}
+
+// GetPackagesBillingOrg returns the free and paid storage used for GitHub Packages
+// in gigabytes for an org.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-github-packages-billing-for-an-organization
+func (s *BillingService) GetPackagesBillingOrg(ctx context.Context, org string) (*PackageBilling, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/settings/billing/packages", org)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	packageBilling := new(PackageBilling)
+	resp, err := s.client.Do(ctx, req, packageBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return packageBilling, resp, nil
+}
+
+// GetStorageBillingOrg returns the estimated paid and estimated total storage used
+// for GitHub Actions and GitHub Packages in gigabytes for an org.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-shared-storage-billing-for-an-organization
+func (s *BillingService) GetStorageBillingOrg(ctx context.Context, org string) (*StorageBilling, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/settings/billing/shared-storage", org)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storageBilling := new(StorageBilling)
+	resp, err := s.client.Do(ctx, req, storageBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return storageBilling, resp, nil
+}
+
+// GetAdvancedSecurityActiveCommittersOrg returns the GitHub Advanced Security active
+// committers for an organization per repository.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#export-advanced-security-active-committers-data-for-an-organization
+func (s *BillingService) GetAdvancedSecurityActiveCommittersOrg(ctx context.Context, org string, opts *ListOptions) (*ActiveCommitters, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/settings/billing/advanced-security", org)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activeCommitters := new(ActiveCommitters)
+	resp, err := s.client.Do(ctx, req, activeCommitters)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return activeCommitters, resp, nil
+}
+
+// GetPremiumRequestsBillingOrg returns the summary of metered GitHub Copilot
+// premium requests (chat/completion usage) for an org.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-github-copilot-premium-requests-billing-for-an-organization
+func (s *BillingService) GetPremiumRequestsBillingOrg(ctx context.Context, org string) (*PremiumRequestsBilling, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/settings/billing/premium_request", org)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	premiumRequestsBilling := new(PremiumRequestsBilling)
+	resp, err := s.client.Do(ctx, req, premiumRequestsBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return premiumRequestsBilling, resp, nil
+}
+
+// GetActionsBillingUser returns the summary of the free and paid GitHub Actions
+// minutes used for a user.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-github-actions-billing-for-a-user
+func (s *BillingService) GetActionsBillingUser(ctx context.Context, user string) (*ActionBilling, *Response, error) {
+	u := fmt.Sprintf("users/%v/settings/billing/actions", user)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actionsBilling := new(ActionBilling)
+	resp, err := s.client.Do(ctx, req, actionsBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return actionsBilling, resp, nil
+}
+
+// GetPackagesBillingUser returns the free and paid storage used for GitHub Packages
+// in gigabytes for a user.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-github-packages-billing-for-a-user
+func (s *BillingService) GetPackagesBillingUser(ctx context.Context, user string) (*PackageBilling, *Response, error) {
+	u := fmt.Sprintf("users/%v/settings/billing/packages", user)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	packageBilling := new(PackageBilling)
+	resp, err := s.client.Do(ctx, req, packageBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return packageBilling, resp, nil
+}
+
+// GetStorageBillingUser returns the estimated paid and estimated total storage used
+// for GitHub Actions and GitHub Packages in gigabytes for a user.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-shared-storage-billing-for-a-user
+func (s *BillingService) GetStorageBillingUser(ctx context.Context, user string) (*StorageBilling, *Response, error) {
+	u := fmt.Sprintf("users/%v/settings/billing/shared-storage", user)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storageBilling := new(StorageBilling)
+	resp, err := s.client.Do(ctx, req, storageBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return storageBilling, resp, nil
+}
+
+// GetPremiumRequestsBillingUser returns the summary of metered GitHub Copilot
+// premium requests (chat/completion usage) for a user.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-github-copilot-premium-requests-billing-for-a-user
+func (s *BillingService) GetPremiumRequestsBillingUser(ctx context.Context, user string) (*PremiumRequestsBilling, *Response, error) {
+	u := fmt.Sprintf("users/%v/settings/billing/premium_request", user)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	premiumRequestsBilling := new(PremiumRequestsBilling)
+	resp, err := s.client.Do(ctx, req, premiumRequestsBilling)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return premiumRequestsBilling, resp, nil
+}
+
+// GetUsageReportOrg returns the usage report for an organization for a given
+// billing period (year, month, day, hour). All fields in opts are optional and
+// narrow down the time period reported on.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-billing-usage-report-for-an-organization
+func (s *BillingService) GetUsageReportOrg(ctx context.Context, org string, opts *UsageReportOptions) (*UsageReport, *Response, error) {
+	u := fmt.Sprintf("organizations/%v/settings/billing/usage", org)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usageReport := new(UsageReport)
+	resp, err := s.client.Do(ctx, req, usageReport)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return usageReport, resp, nil
+}
+
+// GetUsageReportUser returns the usage report for a user for a given billing
+// period (year, month, day, hour). All fields in opts are optional and narrow
+// down the time period reported on.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-billing-usage-report-for-a-user
+func (s *BillingService) GetUsageReportUser(ctx context.Context, user string, opts *UsageReportOptions) (*UsageReport, *Response, error) {
+	u := fmt.Sprintf("users/%v/settings/billing/usage", user)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usageReport := new(UsageReport)
+	resp, err := s.client.Do(ctx, req, usageReport)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return usageReport, resp, nil
+}
+
+// StreamUsageReportOrg issues the same request as GetUsageReportOrg, but decodes
+// the usageItems array one element at a time and invokes fn for each UsageItem
+// instead of buffering the full report in memory. It returns as soon as fn
+// returns an error, and it stops if ctx is canceled between items.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-billing-usage-report-for-an-organization
+func (s *BillingService) StreamUsageReportOrg(ctx context.Context, org string, opts *UsageReportOptions, fn func(*UsageItem) error) error {
+	u := fmt.Sprintf("organizations/%v/settings/billing/usage", org)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return err
+	}
+
+	return s.streamUsageReport(ctx, u, fn)
+}
+
+// StreamUsageReportUser issues the same request as GetUsageReportUser, but decodes
+// the usageItems array one element at a time and invokes fn for each UsageItem
+// instead of buffering the full report in memory. It returns as soon as fn
+// returns an error, and it stops if ctx is canceled between items.
+//
+// GitHub API docs: https://docs.github.com/rest/billing/billing#get-billing-usage-report-for-a-user
+func (s *BillingService) StreamUsageReportUser(ctx context.Context, user string, opts *UsageReportOptions, fn func(*UsageItem) error) error {
+	u := fmt.Sprintf("users/%v/settings/billing/usage", user)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return err
+	}
+
+	return s.streamUsageReport(ctx, u, fn)
+}
+
+// streamUsageReport requests u and decodes the "usageItems" field of the
+// response token-by-token, so that the full array is never materialized in
+// memory. HTTP-level errors are surfaced before any item reaches fn.
+func (s *BillingService) streamUsageReport(ctx context.Context, u string, fn func(*UsageItem) error) error {
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.BareDo(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+
+	if _, err := dec.Token(); err != nil { // consume the report object's opening "{"
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if key != "usageItems" {
+			var ignored any
+			if err := dec.Decode(&ignored); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := dec.Token(); err != nil { // consume the array's opening "["
+			return err
+		}
+		for dec.More() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			item := new(UsageItem)
+			if err := dec.Decode(item); err != nil {
+				return err
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // consume the array's closing "]"
+			return err
+		}
+	}
+
+	return nil
+}
+
+// usageItemCSVHeader is the header row written by ExportUsageReportOrgCSV.
+var usageItemCSVHeader = []string{
+	"date", "product", "sku", "quantity", "unitType", "pricePerUnit",
+	"grossAmount", "discountAmount", "netAmount", "organizationName", "repositoryName",
+}
+
+// ExportUsageReportOrgCSV streams the usage report for an organization to w as
+// CSV: a header row followed by one record per usage item. It is built on top
+// of StreamUsageReportOrg, so the full item list is never held in memory.
+func (s *BillingService) ExportUsageReportOrgCSV(ctx context.Context, org string, opts *UsageReportOptions, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(usageItemCSVHeader); err != nil {
+		return err
+	}
+
+	if err := s.StreamUsageReportOrg(ctx, org, opts, func(item *UsageItem) error {
+		return cw.Write(usageItemCSVRecord(item))
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func usageItemCSVRecord(item *UsageItem) []string {
+	return []string{
+		item.GetDate(),
+		item.GetProduct(),
+		item.GetSKU(),
+		strconv.FormatFloat(item.GetQuantity(), 'f', -1, 64),
+		item.GetUnitType(),
+		strconv.FormatFloat(item.GetPricePerUnit(), 'f', -1, 64),
+		strconv.FormatFloat(item.GetGrossAmount(), 'f', -1, 64),
+		strconv.FormatFloat(item.GetDiscountAmount(), 'f', -1, 64),
+		strconv.FormatFloat(item.GetNetAmount(), 'f', -1, 64),
+		item.GetOrganizationName(),
+		item.GetRepositoryName(),
+	}
+}
+
+// UsageGroupKey identifies a dimension that UsageReport.Aggregate can group
+// usage items by.
+type UsageGroupKey int
+
+// Supported grouping dimensions for UsageReport.Aggregate and UsageReport.TopN.
+const (
+	GroupByProduct UsageGroupKey = iota
+	GroupBySKU
+	GroupByRepository
+	GroupByOrganization
+	GroupByDate
+	GroupByUnitType
+)
+
+// UsageAggregate reports the summed quantity and cost of every usage item
+// sharing one combination of group key values, as produced by
+// UsageReport.Aggregate.
+type UsageAggregate struct {
+	Keys           map[UsageGroupKey]string
+	Quantity       float64
+	GrossAmount    float64
+	DiscountAmount float64
+	NetAmount      float64
+	ItemCount      int
+}
+
+// usageGroupValue returns item's value for the given grouping dimension.
+func usageGroupValue(item *UsageItem, key UsageGroupKey) string {
+	switch key {
+	case GroupByProduct:
+		return item.GetProduct()
+	case GroupBySKU:
+		return item.GetSKU()
+	case GroupByRepository:
+		return item.GetRepositoryName()
+	case GroupByOrganization:
+		return item.GetOrganizationName()
+	case GroupByDate:
+		return item.GetDate()
+	case GroupByUnitType:
+		return item.GetUnitType()
+	default:
+		return ""
+	}
+}
+
+// Aggregate groups the report's usage items by the given keys, summing their
+// Quantity, GrossAmount, DiscountAmount and NetAmount within each group. With
+// no keys, it returns a single UsageAggregate totalling the whole report.
+// Groups are returned in first-seen order.
+func (r *UsageReport) Aggregate(keys ...UsageGroupKey) []UsageAggregate {
+	var order []string
+	groups := make(map[string]*UsageAggregate)
+
+	for _, item := range r.UsageItems {
+		values := make(map[UsageGroupKey]string, len(keys))
+		idParts := make([]string, len(keys))
+		for i, key := range keys {
+			v := usageGroupValue(item, key)
+			values[key] = v
+			idParts[i] = v
+		}
+		id := strings.Join(idParts, "\x00")
+
+		agg, ok := groups[id]
+		if !ok {
+			agg = &UsageAggregate{Keys: values}
+			groups[id] = agg
+			order = append(order, id)
+		}
+
+		agg.Quantity += item.GetQuantity()
+		agg.GrossAmount += item.GetGrossAmount()
+		agg.DiscountAmount += item.GetDiscountAmount()
+		agg.NetAmount += item.GetNetAmount()
+		agg.ItemCount++
+	}
+
+	aggregates := make([]UsageAggregate, 0, len(order))
+	for _, id := range order {
+		aggregates = append(aggregates, *groups[id])
+	}
+	return aggregates
+}
+
+// TopN groups the report by key and returns the n groups with the largest
+// value of by, in descending order, for cost or usage hotspot analysis. If
+// fewer than n groups exist, all of them are returned.
+func (r *UsageReport) TopN(key UsageGroupKey, n int, by func(*UsageAggregate) float64) []UsageAggregate {
+	if n <= 0 {
+		return nil
+	}
+
+	aggregates := r.Aggregate(key)
+	sort.Slice(aggregates, func(i, j int) bool {
+		return by(&aggregates[i]) > by(&aggregates[j])
+	})
+	if n < len(aggregates) {
+		aggregates = aggregates[:n]
+	}
+	return aggregates
+}
+
+// FilterByDateRange returns a new UsageReport containing only the items whose
+// Date falls within [from, to]. Items whose Date cannot be parsed as
+// "2006-01-02" are excluded.
+func (r *UsageReport) FilterByDateRange(from, to time.Time) *UsageReport {
+	filtered := new(UsageReport)
+	for _, item := range r.UsageItems {
+		d, err := time.Parse("2006-01-02", item.GetDate())
+		if err != nil {
+			continue
+		}
+		if d.Before(from) || d.After(to) {
+			continue
+		}
+		filtered.UsageItems = append(filtered.UsageItems, item)
+	}
+	return filtered
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 The go-github AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BudgetScope narrows the usage items that count toward a Budget. A zero
+// value field matches usage items regardless of their value for that
+// dimension.
+type BudgetScope struct {
+	Repository string
+	Product    string
+	SKU        string
+}
+
+// Budget defines a net-spend ceiling that BudgetMonitor watches over a
+// billing period.
+type Budget struct {
+	// Name uniquely identifies this budget among a BudgetConfig's Budgets.
+	Name string
+	// Scope restricts which usage items count toward LimitUSD.
+	Scope BudgetScope
+	// LimitUSD is the budget's net spend ceiling for the billing period.
+	LimitUSD float64
+	// WarnAt are fractions of LimitUSD (e.g. 0.5, 0.8, 1.0) that fire a
+	// BudgetEvent the first time cumulative spend crosses them within a
+	// billing period.
+	WarnAt []float64
+}
+
+// BudgetEventStore persists which (budget, threshold, billing period)
+// combinations have already fired, so that restarting a BudgetMonitor does
+// not re-deliver alerts already sent in the current billing period.
+// BudgetConfig.Store has no default: callers that only need dedup for the
+// lifetime of a single process can pass &MemoryBudgetEventStore{} explicitly;
+// callers that need alerts to survive a restart should supply a store backed
+// by a file or database.
+type BudgetEventStore interface {
+	// Fired reports whether key has already been recorded.
+	Fired(key string) (bool, error)
+	// MarkFired records that key has fired.
+	MarkFired(key string) error
+}
+
+// MemoryBudgetEventStore is a BudgetEventStore that only dedupes for the
+// lifetime of the process; a restart forgets every already-fired threshold.
+// It exists for callers that have explicitly decided they don't need alerts
+// to survive a restart, not as an implicit default.
+type MemoryBudgetEventStore struct {
+	mu    sync.Mutex
+	fired map[string]bool
+}
+
+func (s *MemoryBudgetEventStore) Fired(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fired[key], nil
+}
+
+func (s *MemoryBudgetEventStore) MarkFired(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fired == nil {
+		s.fired = make(map[string]bool)
+	}
+	s.fired[key] = true
+	return nil
+}
+
+// BudgetConfig configures a BudgetMonitor.
+type BudgetConfig struct {
+	Budgets []Budget
+	// Interval is how often Run polls usage. It must be greater than zero.
+	Interval time.Duration
+	// Store persists already-fired thresholds across restarts. It is
+	// required: pass &MemoryBudgetEventStore{} explicitly if dedup only
+	// needs to last for the lifetime of the process.
+	Store BudgetEventStore
+}
+
+// BudgetEvent is delivered to callbacks registered with BudgetMonitor.OnThreshold
+// when a Budget crosses one of its WarnAt thresholds.
+type BudgetEvent struct {
+	Budget        string
+	Threshold     float64
+	SpendUSD      float64
+	LimitUSD      float64
+	BillingPeriod string
+}
+
+// BudgetMonitor polls Billing.GetUsageReportOrg on a configured interval,
+// accumulates net spend per Budget, and fires callbacks registered with
+// OnThreshold the first time a budget crosses one of its thresholds within a
+// billing period.
+type BudgetMonitor struct {
+	client *Client
+	owner  string
+	cfg    BudgetConfig
+	store  BudgetEventStore
+	now    func() time.Time
+
+	mu        sync.Mutex
+	spend     map[string]float64 // budget name -> net spend this period
+	callbacks []func(BudgetEvent)
+}
+
+// errNoBudgetEventStore is returned by NewBudgetMonitor when cfg.Store is
+// nil.
+var errNoBudgetEventStore = errors.New("github: BudgetConfig.Store must be set; pass &MemoryBudgetEventStore{} explicitly if restart-durable alerts are not required")
+
+// NewBudgetMonitor creates a BudgetMonitor that polls owner's usage through
+// client according to cfg. cfg.Store must be set: BudgetMonitor has no
+// implicit in-memory fallback, since silently defaulting to one would
+// re-fire every already-crossed threshold on every process restart, which is
+// exactly what a persisted store exists to avoid.
+func NewBudgetMonitor(client *Client, owner string, cfg BudgetConfig) (*BudgetMonitor, error) {
+	if cfg.Store == nil {
+		return nil, errNoBudgetEventStore
+	}
+
+	return &BudgetMonitor{
+		client: client,
+		owner:  owner,
+		cfg:    cfg,
+		store:  cfg.Store,
+		now:    time.Now,
+		spend:  make(map[string]float64),
+	}, nil
+}
+
+// OnThreshold registers fn to be called whenever a budget crosses one of its
+// configured WarnAt thresholds. fn is called synchronously from within Run
+// (or poll, in tests) and must not block.
+func (m *BudgetMonitor) OnThreshold(fn func(BudgetEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, fn)
+}
+
+// Snapshot returns the current net spend per budget name for the
+// in-progress billing period.
+func (m *BudgetMonitor) Snapshot() map[string]float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]float64, len(m.spend))
+	for name, v := range m.spend {
+		snapshot[name] = v
+	}
+	return snapshot
+}
+
+// errInvalidInterval is returned by Run when BudgetConfig.Interval is not
+// positive.
+var errInvalidInterval = errors.New("github: BudgetConfig.Interval must be greater than zero")
+
+// Run polls usage every cfg.Interval, starting with an immediate poll, until
+// ctx is canceled, at which point it returns ctx.Err(). It returns early if a
+// poll fails, and it returns errInvalidInterval without polling if
+// cfg.Interval is not positive.
+func (m *BudgetMonitor) Run(ctx context.Context) error {
+	if m.cfg.Interval <= 0 {
+		return errInvalidInterval
+	}
+
+	if err := m.poll(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := m.poll(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// poll fetches the current usage report and evaluates every budget against
+// it. There is no concurrent per-repo fetch here: GitHub's billing usage
+// endpoint (GetUsageReportOrg) has no per-repository counterpart and no
+// pagination parameters in UsageReportOptions, so a single request already
+// returns every usageItem for the org, for every repository, in one
+// response. Repository-scoped budgets are evaluated by filtering that one
+// report rather than issuing extra requests there aren't any endpoints to
+// issue.
+func (m *BudgetMonitor) poll(ctx context.Context) error {
+	report, _, err := m.client.Billing.GetUsageReportOrg(ctx, m.owner, nil)
+	if err != nil {
+		return err
+	}
+
+	period := billingPeriod(m.now())
+
+	var events []BudgetEvent
+	for _, budget := range m.cfg.Budgets {
+		budgetEvents, err := m.evaluateBudget(budget, report, period)
+		if err != nil {
+			return err
+		}
+		events = append(events, budgetEvents...)
+	}
+
+	m.mu.Lock()
+	callbacks := append([]func(BudgetEvent){}, m.callbacks...)
+	m.mu.Unlock()
+
+	for _, event := range events {
+		for _, cb := range callbacks {
+			cb(event)
+		}
+	}
+	return nil
+}
+
+// evaluateBudget sums net spend for the items matching budget.Scope, records
+// it as the budget's current spend, and returns any thresholds newly crossed
+// in period.
+func (m *BudgetMonitor) evaluateBudget(budget Budget, report *UsageReport, period string) ([]BudgetEvent, error) {
+	var spend float64
+	for _, item := range report.UsageItems {
+		if budgetScopeMatches(budget.Scope, item) {
+			spend += item.GetNetAmount()
+		}
+	}
+
+	m.mu.Lock()
+	m.spend[budget.Name] = spend
+	m.mu.Unlock()
+
+	var events []BudgetEvent
+	for _, threshold := range budget.WarnAt {
+		if budget.LimitUSD <= 0 || spend < threshold*budget.LimitUSD {
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%v|%s", budget.Name, threshold, period)
+		fired, err := m.store.Fired(key)
+		if err != nil {
+			return nil, err
+		}
+		if fired {
+			continue
+		}
+		if err := m.store.MarkFired(key); err != nil {
+			return nil, err
+		}
+
+		events = append(events, BudgetEvent{
+			Budget:        budget.Name,
+			Threshold:     threshold,
+			SpendUSD:      spend,
+			LimitUSD:      budget.LimitUSD,
+			BillingPeriod: period,
+		})
+	}
+	return events, nil
+}
+
+// budgetScopeMatches reports whether item counts toward scope. A zero-value
+// field in scope matches any value.
+func budgetScopeMatches(scope BudgetScope, item *UsageItem) bool {
+	if scope.Repository != "" && item.GetRepositoryName() != scope.Repository {
+		return false
+	}
+	if scope.Product != "" && item.GetProduct() != scope.Product {
+		return false
+	}
+	if scope.SKU != "" && item.GetSKU() != scope.SKU {
+		return false
+	}
+	return true
+}
+
+// billingPeriod returns the YYYY-MM identifier for the billing period t
+// falls within, used to key already-fired thresholds.
+func billingPeriod(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
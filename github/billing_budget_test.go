@@ -0,0 +1,253 @@
+// Copyright 2026 The go-github AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBudgetMonitor_OnThreshold(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	var netAmount float64
+	mux.HandleFunc("/organizations/o/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"usageItems": [{"product": "Actions", "netAmount": %v}]}`, netAmount)
+	})
+
+	monitor, err := NewBudgetMonitor(client, "o", BudgetConfig{
+		Budgets: []Budget{
+			{
+				Name:     "actions",
+				Scope:    BudgetScope{Product: "Actions"},
+				LimitUSD: 10,
+				WarnAt:   []float64{0.5, 1.0},
+			},
+		},
+		Store: &MemoryBudgetEventStore{},
+	})
+	if err != nil {
+		t.Fatalf("NewBudgetMonitor returned error: %v", err)
+	}
+
+	clock := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return clock }
+
+	var mu sync.Mutex
+	var got []BudgetEvent
+	monitor.OnThreshold(func(e BudgetEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e)
+	})
+
+	ctx := context.Background()
+
+	netAmount = 3 // below the 50% threshold
+	if err := monitor.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("poll fired events before crossing a threshold: %+v", got)
+	}
+	if snap := monitor.Snapshot(); snap["actions"] != 3 {
+		t.Errorf("Snapshot()[actions] = %v, want 3", snap["actions"])
+	}
+
+	netAmount = 6 // crosses 50%
+	if err := monitor.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Threshold != 0.5 {
+		t.Fatalf("poll events = %+v, want a single 0.5 threshold event", got)
+	}
+
+	// Polling again at the same spend level must not re-fire the threshold.
+	if err := monitor.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("poll re-fired an already-crossed threshold: %+v", got)
+	}
+
+	netAmount = 12 // crosses 100%
+	if err := monitor.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if len(got) != 2 || got[1].Threshold != 1.0 {
+		t.Fatalf("poll events = %+v, want a second 1.0 threshold event", got)
+	}
+
+	// Advancing the fake clock into a new billing period resets dedup, so
+	// the same spend level fires again.
+	clock = time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	if err := monitor.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("poll did not re-fire thresholds in the new billing period: %+v", got)
+	}
+}
+
+func TestBudgetMonitor_scopeFiltering(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/organizations/o/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"usageItems": [
+				{"product": "Actions", "repositoryName": "o/a", "netAmount": 5},
+				{"product": "Actions", "repositoryName": "o/b", "netAmount": 5},
+				{"product": "Codespaces", "repositoryName": "o/a", "netAmount": 5}
+			]
+		}`)
+	})
+
+	monitor, err := NewBudgetMonitor(client, "o", BudgetConfig{
+		Budgets: []Budget{
+			{Name: "repo-a", Scope: BudgetScope{Repository: "o/a"}, LimitUSD: 100},
+			{Name: "actions", Scope: BudgetScope{Product: "Actions"}, LimitUSD: 100},
+		},
+		Store: &MemoryBudgetEventStore{},
+	})
+	if err != nil {
+		t.Fatalf("NewBudgetMonitor returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := monitor.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+
+	snap := monitor.Snapshot()
+	if snap["repo-a"] != 10 {
+		t.Errorf("Snapshot()[repo-a] = %v, want 10", snap["repo-a"])
+	}
+	if snap["actions"] != 10 {
+		t.Errorf("Snapshot()[actions] = %v, want 10", snap["actions"])
+	}
+}
+
+func TestBudgetMonitor_persistedStore(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/organizations/o/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"usageItems": [{"product": "Actions", "netAmount": 20}]}`)
+	})
+
+	cfg := BudgetConfig{
+		Budgets: []Budget{{Name: "actions", LimitUSD: 10, WarnAt: []float64{1.0}}},
+		Store:   &MemoryBudgetEventStore{},
+	}
+
+	ctx := context.Background()
+	var fired int
+
+	first, err := NewBudgetMonitor(client, "o", cfg)
+	if err != nil {
+		t.Fatalf("NewBudgetMonitor returned error: %v", err)
+	}
+	first.OnThreshold(func(BudgetEvent) { fired++ })
+	if err := first.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if fired != 1 {
+		t.Fatalf("fired = %d, want 1", fired)
+	}
+
+	// A freshly constructed monitor sharing the same store (simulating a
+	// process restart) must not re-alert for a threshold already recorded.
+	second, err := NewBudgetMonitor(client, "o", cfg)
+	if err != nil {
+		t.Fatalf("NewBudgetMonitor returned error: %v", err)
+	}
+	second.OnThreshold(func(BudgetEvent) { fired++ })
+	if err := second.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if fired != 1 {
+		t.Fatalf("fired = %d after restart, want still 1", fired)
+	}
+}
+
+func TestBudgetMonitor_Run(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	var mu sync.Mutex
+	var polls int
+	mux.HandleFunc("/organizations/o/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		mu.Unlock()
+		fmt.Fprint(w, `{"usageItems": [{"product": "Actions", "netAmount": 1}]}`)
+	})
+
+	monitor, err := NewBudgetMonitor(client, "o", BudgetConfig{
+		Budgets:  []Budget{{Name: "actions", LimitUSD: 100}},
+		Interval: 10 * time.Millisecond,
+		Store:    &MemoryBudgetEventStore{},
+	})
+	if err != nil {
+		t.Fatalf("NewBudgetMonitor returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- monitor.Run(ctx) }()
+
+	// Let Run complete its immediate poll plus at least one ticked poll
+	// before canceling.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	err = <-done
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run returned error %v, want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if polls < 2 {
+		t.Errorf("Run polled %d times, want at least 2 (immediate + ticked)", polls)
+	}
+}
+
+func TestBudgetMonitor_Run_invalidInterval(t *testing.T) {
+	t.Parallel()
+	client, _, _ := setup(t)
+
+	monitor, err := NewBudgetMonitor(client, "o", BudgetConfig{
+		Budgets: []Budget{{Name: "actions", LimitUSD: 100}},
+		Store:   &MemoryBudgetEventStore{},
+	})
+	if err != nil {
+		t.Fatalf("NewBudgetMonitor returned error: %v", err)
+	}
+
+	if err := monitor.Run(context.Background()); !errors.Is(err, errInvalidInterval) {
+		t.Errorf("Run returned error %v, want errInvalidInterval", err)
+	}
+}
+
+func TestNewBudgetMonitor_noStore(t *testing.T) {
+	t.Parallel()
+	client, _, _ := setup(t)
+
+	_, err := NewBudgetMonitor(client, "o", BudgetConfig{
+		Budgets: []Budget{{Name: "actions", LimitUSD: 100}},
+	})
+	if !errors.Is(err, errNoBudgetEventStore) {
+		t.Errorf("NewBudgetMonitor returned error %v, want errNoBudgetEventStore", err)
+	}
+}
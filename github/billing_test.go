@@ -6,10 +6,13 @@
 package github
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -180,6 +183,66 @@ func TestBillingService_GetStorageBillingOrg_invalidOrg(t *testing.T) {
 	testURLParseError(t, err)
 }
 
+func TestBillingService_GetPremiumRequestsBillingOrg(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/orgs/o/settings/billing/premium_request", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+				"total_requests": 1200,
+				"total_paid_requests": 200,
+				"included_requests": 1000,
+				"requests_breakdown": {
+					"gpt-4o": 800,
+					"o1-preview": 400
+				}
+			}`)
+	})
+
+	ctx := context.Background()
+	hook, _, err := client.Billing.GetPremiumRequestsBillingOrg(ctx, "o")
+	if err != nil {
+		t.Errorf("Billing.GetPremiumRequestsBillingOrg returned error: %v", err)
+	}
+
+	want := &PremiumRequestsBilling{
+		TotalRequests:     1200,
+		TotalPaidRequests: 200,
+		IncludedRequests:  1000,
+		RequestsBreakdown: map[string]int{
+			"gpt-4o":     800,
+			"o1-preview": 400,
+		},
+	}
+	if !cmp.Equal(hook, want) {
+		t.Errorf("Billing.GetPremiumRequestsBillingOrg returned %+v, want %+v", hook, want)
+	}
+
+	const methodName = "GetPremiumRequestsBillingOrg"
+	testBadOptions(t, methodName, func() (err error) {
+		_, _, err = client.Billing.GetPremiumRequestsBillingOrg(ctx, "\n")
+		return err
+	})
+
+	testNewRequestAndDoFailure(t, methodName, client, func() (*Response, error) {
+		got, resp, err := client.Billing.GetPremiumRequestsBillingOrg(ctx, "o")
+		if got != nil {
+			t.Errorf("testNewRequestAndDoFailure %v = %#v, want nil", methodName, got)
+		}
+		return resp, err
+	})
+}
+
+func TestBillingService_GetPremiumRequestsBillingOrg_invalidOrg(t *testing.T) {
+	t.Parallel()
+	client, _, _ := setup(t)
+
+	ctx := context.Background()
+	_, _, err := client.Billing.GetPremiumRequestsBillingOrg(ctx, "%")
+	testURLParseError(t, err)
+}
+
 func TestBillingService_GetActionsBillingUser(t *testing.T) {
 	t.Parallel()
 	client, mux, _ := setup(t)
@@ -346,6 +409,64 @@ func TestBillingService_GetStorageBillingUser_invalidUser(t *testing.T) {
 	testURLParseError(t, err)
 }
 
+func TestBillingService_GetPremiumRequestsBillingUser(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/users/u/settings/billing/premium_request", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+				"total_requests": 50,
+				"total_paid_requests": 0,
+				"included_requests": 300,
+				"requests_breakdown": {
+					"gpt-4o": 50
+				}
+			}`)
+	})
+
+	ctx := context.Background()
+	hook, _, err := client.Billing.GetPremiumRequestsBillingUser(ctx, "u")
+	if err != nil {
+		t.Errorf("Billing.GetPremiumRequestsBillingUser returned error: %v", err)
+	}
+
+	want := &PremiumRequestsBilling{
+		TotalRequests:     50,
+		TotalPaidRequests: 0,
+		IncludedRequests:  300,
+		RequestsBreakdown: map[string]int{
+			"gpt-4o": 50,
+		},
+	}
+	if !cmp.Equal(hook, want) {
+		t.Errorf("Billing.GetPremiumRequestsBillingUser returned %+v, want %+v", hook, want)
+	}
+
+	const methodName = "GetPremiumRequestsBillingUser"
+	testBadOptions(t, methodName, func() (err error) {
+		_, _, err = client.Billing.GetPremiumRequestsBillingUser(ctx, "\n")
+		return err
+	})
+
+	testNewRequestAndDoFailure(t, methodName, client, func() (*Response, error) {
+		got, resp, err := client.Billing.GetPremiumRequestsBillingUser(ctx, "u")
+		if got != nil {
+			t.Errorf("testNewRequestAndDoFailure %v = %#v, want nil", methodName, got)
+		}
+		return resp, err
+	})
+}
+
+func TestBillingService_GetPremiumRequestsBillingUser_invalidUser(t *testing.T) {
+	t.Parallel()
+	client, _, _ := setup(t)
+
+	ctx := context.Background()
+	_, _, err := client.Billing.GetPremiumRequestsBillingUser(ctx, "%")
+	testURLParseError(t, err)
+}
+
 func TestMinutesUsedBreakdown_Marshal(t *testing.T) {
 	t.Parallel()
 	testJSONMarshal(t, &MinutesUsedBreakdown{}, "{}")
@@ -394,6 +515,31 @@ func TestActionBilling_Marshal(t *testing.T) {
 	testJSONMarshal(t, u, want)
 }
 
+func TestPremiumRequestsBilling_Marshal(t *testing.T) {
+	t.Parallel()
+	testJSONMarshal(t, &PremiumRequestsBilling{}, "{}")
+
+	u := &PremiumRequestsBilling{
+		TotalRequests:     1,
+		TotalPaidRequests: 1,
+		IncludedRequests:  1,
+		RequestsBreakdown: map[string]int{
+			"gpt-4o": 1,
+		},
+	}
+
+	want := `{
+		"total_requests": 1,
+		"total_paid_requests": 1,
+		"included_requests": 1,
+		"requests_breakdown": {
+			"gpt-4o": 1
+		}
+	}`
+
+	testJSONMarshal(t, u, want)
+}
+
 func TestPackageBilling_Marshal(t *testing.T) {
 	t.Parallel()
 	testJSONMarshal(t, &PackageBilling{}, "{}")
@@ -674,3 +820,214 @@ func TestBillingService_GetUsageReportUser_invalidUser(t *testing.T) {
 	_, _, err := client.Billing.GetUsageReportUser(ctx, "%", nil)
 	testURLParseError(t, err)
 }
+
+func TestBillingService_StreamUsageReportOrg(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/organizations/o/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+			"usageItems": [
+				{"date": "2023-08-01", "product": "Actions", "sku": "Actions Linux", "quantity": 100, "netAmount": 0.8},
+				{"date": "2023-08-02", "product": "Codespaces", "sku": "Codespaces Linux", "quantity": 50, "netAmount": 8.0}
+			]
+		}`)
+	})
+
+	ctx := context.Background()
+	var got []*UsageItem
+	err := client.Billing.StreamUsageReportOrg(ctx, "o", nil, func(item *UsageItem) error {
+		got = append(got, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Billing.StreamUsageReportOrg returned error: %v", err)
+	}
+
+	want := []*UsageItem{
+		{Date: Ptr("2023-08-01"), Product: Ptr("Actions"), SKU: Ptr("Actions Linux"), Quantity: Ptr(100.0), NetAmount: Ptr(0.8)},
+		{Date: Ptr("2023-08-02"), Product: Ptr("Codespaces"), SKU: Ptr("Codespaces Linux"), Quantity: Ptr(50.0), NetAmount: Ptr(8.0)},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Billing.StreamUsageReportOrg streamed %+v, want %+v", got, want)
+	}
+}
+
+func TestBillingService_StreamUsageReportOrg_callbackError(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/organizations/o/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"usageItems": [{"date": "2023-08-01"}, {"date": "2023-08-02"}]}`)
+	})
+
+	ctx := context.Background()
+	wantErr := errors.New("stop")
+	var calls int
+	err := client.Billing.StreamUsageReportOrg(ctx, "o", nil, func(item *UsageItem) error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Billing.StreamUsageReportOrg returned error %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("Billing.StreamUsageReportOrg invoked fn %d times, want 1", calls)
+	}
+}
+
+func TestBillingService_StreamUsageReportUser(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/users/u/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+			"usageItems": [
+				{"date": "2023-08-15", "product": "Codespaces", "sku": "Codespaces Linux", "quantity": 50, "netAmount": 8.0}
+			]
+		}`)
+	})
+
+	ctx := context.Background()
+	var got []*UsageItem
+	err := client.Billing.StreamUsageReportUser(ctx, "u", nil, func(item *UsageItem) error {
+		got = append(got, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Billing.StreamUsageReportUser returned error: %v", err)
+	}
+
+	want := []*UsageItem{
+		{Date: Ptr("2023-08-15"), Product: Ptr("Codespaces"), SKU: Ptr("Codespaces Linux"), Quantity: Ptr(50.0), NetAmount: Ptr(8.0)},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Billing.StreamUsageReportUser streamed %+v, want %+v", got, want)
+	}
+}
+
+func TestBillingService_ExportUsageReportOrgCSV(t *testing.T) {
+	t.Parallel()
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/organizations/o/settings/billing/usage", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"usageItems": [
+				{
+					"date": "2023-08-01",
+					"product": "Actions",
+					"sku": "Actions Linux",
+					"quantity": 100,
+					"unitType": "minutes",
+					"pricePerUnit": 0.008,
+					"grossAmount": 0.8,
+					"discountAmount": 0,
+					"netAmount": 0.8,
+					"organizationName": "GitHub",
+					"repositoryName": "github/example"
+				}
+			]
+		}`)
+	})
+
+	ctx := context.Background()
+	var buf bytes.Buffer
+	if err := client.Billing.ExportUsageReportOrgCSV(ctx, "o", nil, &buf); err != nil {
+		t.Fatalf("Billing.ExportUsageReportOrgCSV returned error: %v", err)
+	}
+
+	want := "date,product,sku,quantity,unitType,pricePerUnit,grossAmount,discountAmount,netAmount,organizationName,repositoryName\n" +
+		"2023-08-01,Actions,Actions Linux,100,minutes,0.008,0.8,0,0.8,GitHub,github/example\n"
+	if buf.String() != want {
+		t.Errorf("Billing.ExportUsageReportOrgCSV wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func testUsageReportForAggregation() *UsageReport {
+	return &UsageReport{
+		UsageItems: []*UsageItem{
+			{
+				Date: Ptr("2023-08-01"), Product: Ptr("Actions"), SKU: Ptr("Actions Linux"),
+				Quantity: Ptr(100.0), UnitType: Ptr("minutes"), GrossAmount: Ptr(0.5), DiscountAmount: Ptr(0.0), NetAmount: Ptr(0.5),
+				RepositoryName: Ptr("github/example"),
+			},
+			{
+				Date: Ptr("2023-08-02"), Product: Ptr("Actions"), SKU: Ptr("Actions Linux"),
+				Quantity: Ptr(50.0), UnitType: Ptr("minutes"), GrossAmount: Ptr(0.25), DiscountAmount: Ptr(0.0), NetAmount: Ptr(0.25),
+				RepositoryName: Ptr("github/example"),
+			},
+			{
+				Date: Ptr("2023-08-02"), Product: Ptr("Codespaces"), SKU: Ptr("Codespaces Linux"),
+				Quantity: Ptr(10.0), UnitType: Ptr("hours"), GrossAmount: Ptr(9.0), DiscountAmount: Ptr(1.0), NetAmount: Ptr(8.0),
+				RepositoryName: Ptr("github/other"),
+			},
+		},
+	}
+}
+
+func TestUsageReport_Aggregate(t *testing.T) {
+	t.Parallel()
+	report := testUsageReportForAggregation()
+
+	got := report.Aggregate(GroupByProduct)
+	want := []UsageAggregate{
+		{Keys: map[UsageGroupKey]string{GroupByProduct: "Actions"}, Quantity: 150, GrossAmount: 0.75, DiscountAmount: 0, NetAmount: 0.75, ItemCount: 2},
+		{Keys: map[UsageGroupKey]string{GroupByProduct: "Codespaces"}, Quantity: 10, GrossAmount: 9.0, DiscountAmount: 1.0, NetAmount: 8.0, ItemCount: 1},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("UsageReport.Aggregate(GroupByProduct) = %+v, want %+v", got, want)
+	}
+}
+
+func TestUsageReport_Aggregate_noKeys(t *testing.T) {
+	t.Parallel()
+	report := testUsageReportForAggregation()
+
+	got := report.Aggregate()
+	want := []UsageAggregate{
+		{Keys: map[UsageGroupKey]string{}, Quantity: 160, GrossAmount: 9.75, DiscountAmount: 1.0, NetAmount: 8.75, ItemCount: 3},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("UsageReport.Aggregate() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUsageReport_TopN(t *testing.T) {
+	t.Parallel()
+	report := testUsageReportForAggregation()
+
+	got := report.TopN(GroupByProduct, 1, func(a *UsageAggregate) float64 { return a.NetAmount })
+	if len(got) != 1 || got[0].Keys[GroupByProduct] != "Codespaces" {
+		t.Errorf("UsageReport.TopN(GroupByProduct, 1, netAmount) = %+v, want top group Codespaces", got)
+	}
+}
+
+func TestUsageReport_TopN_negativeN(t *testing.T) {
+	t.Parallel()
+	report := testUsageReportForAggregation()
+
+	got := report.TopN(GroupByProduct, -1, func(a *UsageAggregate) float64 { return a.NetAmount })
+	if len(got) != 0 {
+		t.Errorf("UsageReport.TopN with n=-1 returned %+v, want empty", got)
+	}
+}
+
+func TestUsageReport_FilterByDateRange(t *testing.T) {
+	t.Parallel()
+	report := testUsageReportForAggregation()
+
+	from := time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.August, 31, 0, 0, 0, 0, time.UTC)
+	got := report.FilterByDateRange(from, to)
+
+	if len(got.UsageItems) != 2 {
+		t.Fatalf("UsageReport.FilterByDateRange returned %d items, want 2", len(got.UsageItems))
+	}
+	for _, item := range got.UsageItems {
+		if item.GetDate() != "2023-08-02" {
+			t.Errorf("UsageReport.FilterByDateRange included item dated %s, want 2023-08-02", item.GetDate())
+		}
+	}
+}